@@ -0,0 +1,34 @@
+// Package creds resolves and persists per-host git credentials so private
+// repositories work without the user passing a token on every invocation.
+// Tokens are looked up, in order, from the environment, the GitHub CLI's
+// own config, and a local store (TokenForHost), none of which prompts.
+// PromptToken separately asks the user, when stdin is a terminal (IsTerminal),
+// to paste a personal access token directly, since
+// GitHub removed username/password API authentication (and the OAuth
+// Authorizations API that used to exchange it for a token) in November
+// 2020.
+package creds
+
+import "os"
+
+// TokenForHost resolves a token for host without prompting: GITHUB_TOKEN or
+// GH_TOKEN (github.com only), then the gh CLI's hosts.yml, then the local
+// credentials store. Returns "" if none are available.
+func TokenForHost(host string) string {
+	if host == "github.com" {
+		if t := os.Getenv("GITHUB_TOKEN"); t != "" {
+			return t
+		}
+		if t := os.Getenv("GH_TOKEN"); t != "" {
+			return t
+		}
+	}
+	if t := tokenFromGHHosts(host); t != "" {
+		return t
+	}
+	store, err := LoadStore()
+	if err != nil {
+		return ""
+	}
+	return store.Token(host)
+}
@@ -0,0 +1,29 @@
+package creds
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+)
+
+// tokenFromGHHosts reads the oauth_token for host from the GitHub CLI's
+// config at ~/.config/gh/hosts.yml, so project-puller can reuse a `gh auth
+// login` session. Returns "" if the file or host entry is missing.
+func tokenFromGHHosts(host string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".config", "gh", "hosts.yml"))
+	if err != nil {
+		return ""
+	}
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return ""
+	}
+	return hosts[host].OAuthToken
+}
@@ -0,0 +1,29 @@
+package creds
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// IsTerminal reports whether stdin is an interactive terminal, so callers
+// know whether prompting is possible.
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// PromptToken asks for a GitHub personal access token on stdin. A pasted
+// PAT is the only interactive credential project-puller can obtain now that
+// GitHub requires one for API access; generate one at
+// https://github.com/settings/tokens.
+func PromptToken() (string, error) {
+	fmt.Print("GitHub personal access token (https://github.com/settings/tokens): ")
+	tokenBytes, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("read token: %w", err)
+	}
+	return strings.TrimSpace(string(tokenBytes)), nil
+}
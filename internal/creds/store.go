@@ -0,0 +1,76 @@
+package creds
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Store is the on-disk token cache at ~/.config/project-puller/credentials.yml,
+// keyed by host, so an interactive credential prompt only happens once per
+// host.
+type Store struct {
+	path   string
+	Tokens map[string]string `yaml:"tokens"`
+}
+
+func storePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("find home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "project-puller", "credentials.yml"), nil
+}
+
+// LoadStore reads the credentials store, returning an empty one if it
+// doesn't exist yet.
+func LoadStore() (*Store, error) {
+	path, err := storePath()
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{path: path, Tokens: map[string]string{}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Token returns the stored token for host, or "" if none.
+func (s *Store) Token(host string) string {
+	return s.Tokens[host]
+}
+
+// SetToken records token for host and persists the store to disk with 0600
+// permissions.
+func (s *Store) SetToken(host, token string) error {
+	if s.Tokens == nil {
+		s.Tokens = map[string]string{}
+	}
+	s.Tokens[host] = token
+	return s.save()
+}
+
+func (s *Store) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(s.path), err)
+	}
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encode credentials: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("write %s: %w", s.path, err)
+	}
+	return nil
+}
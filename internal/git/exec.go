@@ -0,0 +1,188 @@
+package git
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// execBackend shells out to the system "git" binary. This was project-puller's
+// original (and only) behavior.
+type execBackend struct {
+	quiet bool
+}
+
+func (b *execBackend) CloneOrPull(opts Options) error {
+	progress := progressWriter(opts)
+
+	if opts.Bare || opts.Mirror {
+		return b.cloneOrPullBare(opts, progress)
+	}
+
+	gitDir := filepath.Join(opts.TargetPath, ".git")
+	exists := false
+	if fi, err := os.Stat(gitDir); err == nil && fi.IsDir() {
+		exists = true
+	}
+
+	if exists {
+		fmt.Fprintf(progress, "Pulling %s\n", opts.TargetPath)
+		if opts.ForkURL != "" {
+			if err := b.ensureUpstreamOriginRemotes(opts); err != nil {
+				return err
+			}
+			if err := b.runGitWithAuth(exec.Command("git", "pull", "upstream"), opts.TargetPath, opts.Auth.Token); err != nil {
+				return err
+			}
+		} else if err := b.runGitWithAuth(exec.Command("git", "pull"), opts.TargetPath, opts.Auth.Token); err != nil {
+			return err
+		}
+		return b.checkoutRefAndSubdir(opts)
+	}
+
+	if opts.ForkURL != "" {
+		fmt.Fprintf(progress, "Cloning %s -> %s (upstream)\n", opts.RepoURL, opts.TargetPath)
+		if err := b.runGitWithAuth(exec.Command("git", "clone", "-o", "upstream", opts.RepoURL, opts.TargetPath), ".", opts.Auth.Token); err != nil {
+			return err
+		}
+		if err := b.addOriginRemote(opts.TargetPath, opts.ForkURL); err != nil {
+			return err
+		}
+		return b.checkoutRefAndSubdir(opts)
+	}
+
+	fmt.Fprintf(progress, "Cloning %s -> %s\n", opts.RepoURL, opts.TargetPath)
+	if err := b.runGitWithAuth(exec.Command("git", "clone", opts.RepoURL, opts.TargetPath), ".", opts.Auth.Token); err != nil {
+		return err
+	}
+	return b.checkoutRefAndSubdir(opts)
+}
+
+// cloneOrPullBare handles Bare and Mirror repos, which have no working
+// tree: the repo's files live directly in TargetPath rather than under a
+// ".git" subdirectory, so pulls run git fetch/remote update instead of git
+// pull, and Ref/Subdir (which need a worktree) are not applied.
+func (b *execBackend) cloneOrPullBare(opts Options, progress io.Writer) error {
+	exists := false
+	if fi, err := os.Stat(opts.TargetPath); err == nil && fi.IsDir() {
+		exists = true
+	}
+
+	if exists {
+		if opts.Mirror {
+			fmt.Fprintf(progress, "Updating mirror %s\n", opts.TargetPath)
+			return b.runGitWithAuth(exec.Command("git", "remote", "update"), opts.TargetPath, opts.Auth.Token)
+		}
+		fmt.Fprintf(progress, "Fetching %s\n", opts.TargetPath)
+		return b.runGitWithAuth(exec.Command("git", "fetch", "origin"), opts.TargetPath, opts.Auth.Token)
+	}
+
+	mode := "--bare"
+	if opts.Mirror {
+		mode = "--mirror"
+	}
+	fmt.Fprintf(progress, "Cloning %s -> %s (%s)\n", opts.RepoURL, opts.TargetPath, strings.TrimPrefix(mode, "--"))
+	return b.runGitWithAuth(exec.Command("git", "clone", mode, opts.RepoURL, opts.TargetPath), ".", opts.Auth.Token)
+}
+
+// checkoutRefAndSubdir checks out opts.Ref (a branch, tag, or commit SHA)
+// when present, falling back to fetching it directly for commits the clone
+// didn't already fetch, then narrows the worktree to opts.Subdir via
+// sparse-checkout when present.
+func (b *execBackend) checkoutRefAndSubdir(opts Options) error {
+	if opts.Ref != "" {
+		if err := b.runGit(exec.Command("git", "checkout", opts.Ref), opts.TargetPath); err != nil {
+			if fetchErr := b.runGitWithAuth(exec.Command("git", "fetch", "origin", opts.Ref), opts.TargetPath, opts.Auth.Token); fetchErr != nil {
+				return fmt.Errorf("checkout %s in %s: %w", opts.Ref, opts.TargetPath, err)
+			}
+			if err := b.runGit(exec.Command("git", "checkout", "FETCH_HEAD"), opts.TargetPath); err != nil {
+				return fmt.Errorf("checkout %s in %s: %w", opts.Ref, opts.TargetPath, err)
+			}
+		}
+	}
+	if opts.Subdir != "" {
+		if err := b.runGit(exec.Command("git", "sparse-checkout", "set", opts.Subdir), opts.TargetPath); err != nil {
+			return fmt.Errorf("sparse-checkout %s in %s: %w", opts.Subdir, opts.TargetPath, err)
+		}
+	}
+	return nil
+}
+
+// ensureUpstreamOriginRemotes ensures upstream (project) and origin (fork)
+// exist; normalizes repos cloned before --username was passed.
+func (b *execBackend) ensureUpstreamOriginRemotes(opts Options) error {
+	hasUpstream := remoteExists(opts.TargetPath, "upstream")
+	hasOrigin := remoteExists(opts.TargetPath, "origin")
+
+	if hasUpstream && !hasOrigin {
+		return b.addOriginRemote(opts.TargetPath, opts.ForkURL)
+	}
+	if !hasUpstream && hasOrigin {
+		// Repo was cloned without a fork; origin is the project. Rename to
+		// upstream and add origin as the fork.
+		if err := b.runGit(exec.Command("git", "remote", "rename", "origin", "upstream"), opts.TargetPath); err != nil {
+			return err
+		}
+		return b.addOriginRemote(opts.TargetPath, opts.ForkURL)
+	}
+	// Both exist or neither; if both exist we do nothing. If neither exists
+	// something is wrong; pull will fail.
+	return nil
+}
+
+func remoteExists(dir, name string) bool {
+	c := exec.Command("git", "remote", "get-url", name)
+	c.Dir = dir
+	return c.Run() == nil
+}
+
+// runGit runs cmd in dir, wiring stdout/stderr when not quiet.
+func (b *execBackend) runGit(cmd *exec.Cmd, dir string) error {
+	cmd.Dir = dir
+	if !b.quiet {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+	return cmd.Run()
+}
+
+// runGitWithAuth runs cmd like runGit, but if token is non-empty, supplies it
+// to git as an HTTP Authorization header via the GIT_CONFIG_* environment
+// variables rather than embedding it in the remote URL: a URL credential
+// gets logged in clone/pull progress output, shows up in `ps`/
+// /proc/<pid>/cmdline, and (for clone) is written verbatim into the new
+// repo's .git/config, none of which this env-only form does.
+func (b *execBackend) runGitWithAuth(cmd *exec.Cmd, dir, token string) error {
+	if token != "" {
+		cmd.Env = append(os.Environ(), authEnv(token)...)
+	}
+	return b.runGit(cmd, dir)
+}
+
+func (b *execBackend) addOriginRemote(targetPath, url string) error {
+	return b.runGit(exec.Command("git", "remote", "add", "origin", url), targetPath)
+}
+
+// authEnv returns GIT_CONFIG_* environment variables that set
+// http.extraHeader to a Basic-auth Authorization header for token, the same
+// convention GitHub's HTTPS git access uses for personal access tokens.
+func authEnv(token string) []string {
+	encoded := base64.StdEncoding.EncodeToString([]byte(token + ":x-oauth-basic"))
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraheader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic " + encoded,
+	}
+}
+
+// progressWriter returns opts.Progress, defaulting to os.Stderr.
+func progressWriter(opts Options) io.Writer {
+	if opts.Progress != nil {
+		return opts.Progress
+	}
+	return os.Stderr
+}
@@ -0,0 +1,201 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gogithttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gogitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// goGitBackend clones and pulls using github.com/go-git/go-git/v5, so no
+// system "git" binary is required.
+type goGitBackend struct {
+	quiet bool
+}
+
+func (b *goGitBackend) CloneOrPull(opts Options) error {
+	progress := progressWriter(opts)
+	if b.quiet {
+		progress = io.Discard
+	}
+
+	auth, err := authMethod(opts.RepoURL, opts.Auth)
+	if err != nil {
+		return err
+	}
+
+	if opts.Subdir != "" {
+		return fmt.Errorf("subdir checkout (%s) is not supported by the go-git backend; use --backend=exec", opts.Subdir)
+	}
+	if opts.Mirror {
+		return fmt.Errorf("--mirror is not supported by the go-git backend; use --backend=exec")
+	}
+
+	repo, err := gogit.PlainOpen(opts.TargetPath)
+	if errors.Is(err, gogit.ErrRepositoryNotExists) {
+		if err := b.clone(opts, auth, progress); err != nil {
+			return err
+		}
+		repo, err = gogit.PlainOpen(opts.TargetPath)
+		if err != nil {
+			return fmt.Errorf("open %s: %w", opts.TargetPath, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("open %s: %w", opts.TargetPath, err)
+	} else if opts.Bare {
+		if err := b.fetch(repo, opts, auth, progress); err != nil {
+			return err
+		}
+	} else if err := b.pull(repo, opts, auth, progress); err != nil {
+		return err
+	}
+
+	if opts.Ref == "" || opts.Bare {
+		return nil
+	}
+	return checkoutRef(repo, opts.Ref)
+}
+
+func (b *goGitBackend) clone(opts Options, auth transport.AuthMethod, progress io.Writer) error {
+	remoteName := "origin"
+	if opts.ForkURL != "" {
+		fmt.Fprintf(os.Stderr, "Cloning %s -> %s (upstream)\n", opts.RepoURL, opts.TargetPath)
+		remoteName = "upstream"
+	} else {
+		fmt.Fprintf(os.Stderr, "Cloning %s -> %s\n", opts.RepoURL, opts.TargetPath)
+	}
+
+	repo, err := gogit.PlainClone(opts.TargetPath, opts.Bare, &gogit.CloneOptions{
+		URL:        opts.RepoURL,
+		Auth:       auth,
+		Progress:   progress,
+		RemoteName: remoteName,
+	})
+	if err != nil {
+		return fmt.Errorf("clone %s: %w", opts.RepoURL, err)
+	}
+	if opts.ForkURL == "" || opts.Bare {
+		return nil
+	}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{opts.ForkURL}}); err != nil {
+		return fmt.Errorf("add origin remote for %s: %w", opts.TargetPath, err)
+	}
+	return nil
+}
+
+func (b *goGitBackend) pull(repo *gogit.Repository, opts Options, auth transport.AuthMethod, progress io.Writer) error {
+	fmt.Fprintf(os.Stderr, "Pulling %s\n", opts.TargetPath)
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("worktree for %s: %w", opts.TargetPath, err)
+	}
+
+	remoteName := "origin"
+	if opts.ForkURL != "" {
+		remoteName = "upstream"
+		if err := ensureRemote(repo, "origin", opts.ForkURL); err != nil {
+			return fmt.Errorf("add origin remote for %s: %w", opts.TargetPath, err)
+		}
+	}
+
+	err = wt.Pull(&gogit.PullOptions{RemoteName: remoteName, Auth: auth, Progress: progress})
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("pull %s: %w", opts.TargetPath, err)
+	}
+	return nil
+}
+
+// fetch updates a bare repo in place; bare repos have no worktree to pull into.
+func (b *goGitBackend) fetch(repo *gogit.Repository, opts Options, auth transport.AuthMethod, progress io.Writer) error {
+	fmt.Fprintf(os.Stderr, "Fetching %s\n", opts.TargetPath)
+	err := repo.Fetch(&gogit.FetchOptions{RemoteName: "origin", Auth: auth, Progress: progress})
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("fetch %s: %w", opts.TargetPath, err)
+	}
+	return nil
+}
+
+// checkoutRef resolves ref as a branch, tag, or commit SHA via
+// Repository.ResolveRevision and checks out the resulting commit. A branch
+// other than the default one exists only as a remote-tracking ref after a
+// clone, so ref is also tried under refs/remotes/upstream/ and
+// refs/remotes/origin/.
+func checkoutRef(repo *gogit.Repository, ref string) error {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("worktree: %w", err)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		for _, remote := range []string{"upstream", "origin"} {
+			if h, rerr := repo.ResolveRevision(plumbing.Revision("refs/remotes/" + remote + "/" + ref)); rerr == nil {
+				hash, err = h, nil
+				break
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("resolve ref %s: %w", ref, err)
+	}
+	if err := wt.Checkout(&gogit.CheckoutOptions{Hash: *hash}); err != nil {
+		return fmt.Errorf("checkout %s: %w", ref, err)
+	}
+	return nil
+}
+
+func ensureRemote(repo *gogit.Repository, name, url string) error {
+	if _, err := repo.Remote(name); err == nil {
+		return nil
+	}
+	_, err := repo.CreateRemote(&config.RemoteConfig{Name: name, URLs: []string{url}})
+	return err
+}
+
+// authMethod picks an auth strategy for repoURL: http(s):// remotes get HTTP
+// basic auth when credentials are supplied and no auth otherwise; all other
+// remotes get SSH public-key auth discovered from ~/.ssh/id_rsa (or
+// Auth.SSHKeyPath) and, failing that, the SSH agent via SSH_AUTH_SOCK.
+func authMethod(repoURL string, a Auth) (transport.AuthMethod, error) {
+	if u, err := url.Parse(repoURL); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		if a.Token != "" {
+			return &gogithttp.BasicAuth{Username: a.Token, Password: "x-oauth-basic"}, nil
+		}
+		if a.Username != "" || a.Password != "" {
+			return &gogithttp.BasicAuth{Username: a.Username, Password: a.Password}, nil
+		}
+		return nil, nil
+	}
+
+	keyPath := a.SSHKeyPath
+	if keyPath == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			keyPath = filepath.Join(home, ".ssh", "id_rsa")
+		}
+	}
+	if keyPath != "" {
+		if _, err := os.Stat(keyPath); err == nil {
+			auth, err := gogitssh.NewPublicKeysFromFile("git", keyPath, "")
+			if err != nil {
+				return nil, fmt.Errorf("load SSH key %s: %w", keyPath, err)
+			}
+			return auth, nil
+		}
+	}
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		auth, err := gogitssh.NewSSHAgentAuth("git")
+		if err != nil {
+			return nil, fmt.Errorf("connect to SSH agent at %s: %w", sock, err)
+		}
+		return auth, nil
+	}
+	return nil, fmt.Errorf("no SSH key found at %s and SSH_AUTH_SOCK is not set", keyPath)
+}
@@ -0,0 +1,95 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Plan describes what CloneOrPull would do for a given Options without
+// doing it: a short Action ("clone", "pull", "clone-bare", "fetch-bare",
+// "clone-mirror", "update-mirror") and the sequence of git commands that
+// would run, in the form the exec backend would invoke them.
+type Plan struct {
+	Action   string
+	Commands []string
+}
+
+// DescribePlan inspects the filesystem at opts.TargetPath (read-only) and
+// reports what CloneOrPull would do, for --dry-run and --output-format=json.
+func DescribePlan(opts Options) Plan {
+	if opts.Bare || opts.Mirror {
+		return describeBarePlan(opts)
+	}
+
+	gitDir := filepath.Join(opts.TargetPath, ".git")
+	exists := false
+	if fi, err := os.Stat(gitDir); err == nil && fi.IsDir() {
+		exists = true
+	}
+
+	var commands []string
+	if exists {
+		if opts.ForkURL != "" {
+			hasUpstream := remoteExists(opts.TargetPath, "upstream")
+			hasOrigin := remoteExists(opts.TargetPath, "origin")
+			switch {
+			case hasUpstream && !hasOrigin:
+				commands = append(commands, fmt.Sprintf("git remote add origin %s", opts.ForkURL))
+			case !hasUpstream && hasOrigin:
+				commands = append(commands,
+					"git remote rename origin upstream",
+					fmt.Sprintf("git remote add origin %s", opts.ForkURL))
+			}
+			commands = append(commands, "git pull upstream")
+		} else {
+			commands = append(commands, "git pull")
+		}
+		return Plan{Action: "pull", Commands: append(commands, refAndSubdirCommands(opts)...)}
+	}
+
+	if opts.ForkURL != "" {
+		commands = append(commands,
+			fmt.Sprintf("git clone -o upstream %s %s", opts.RepoURL, opts.TargetPath),
+			fmt.Sprintf("git remote add origin %s", opts.ForkURL))
+	} else {
+		commands = append(commands, fmt.Sprintf("git clone %s %s", opts.RepoURL, opts.TargetPath))
+	}
+	return Plan{Action: "clone", Commands: append(commands, refAndSubdirCommands(opts)...)}
+}
+
+func describeBarePlan(opts Options) Plan {
+	exists := false
+	if fi, err := os.Stat(opts.TargetPath); err == nil && fi.IsDir() {
+		exists = true
+	}
+
+	if exists {
+		if opts.Mirror {
+			return Plan{Action: "update-mirror", Commands: []string{"git remote update"}}
+		}
+		return Plan{Action: "fetch-bare", Commands: []string{"git fetch origin"}}
+	}
+
+	mode := "--bare"
+	action := "clone-bare"
+	if opts.Mirror {
+		mode = "--mirror"
+		action = "clone-mirror"
+	}
+	return Plan{
+		Action:   action,
+		Commands: []string{fmt.Sprintf("git clone %s %s %s", mode, opts.RepoURL, opts.TargetPath)},
+	}
+}
+
+func refAndSubdirCommands(opts Options) []string {
+	var commands []string
+	if opts.Ref != "" {
+		commands = append(commands, fmt.Sprintf("git checkout %s", opts.Ref))
+	}
+	if opts.Subdir != "" {
+		commands = append(commands, fmt.Sprintf("git sparse-checkout set %s", opts.Subdir))
+	}
+	return commands
+}
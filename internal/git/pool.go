@@ -0,0 +1,66 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Job pairs Options with a human-readable name for error reporting.
+type Job struct {
+	Name string
+	Options
+}
+
+// Result is the outcome of running a single Job via RunAllResults.
+type Result struct {
+	Name     string
+	Err      error
+	Duration time.Duration
+}
+
+// RunAllResults runs every job against backend, at most concurrency at a
+// time, and returns one Result per job in the same order as jobs. Errors
+// from individual repos do not abort the run; every job always runs.
+func RunAllResults(backend Backend, jobs []Job, concurrency int) []Result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]Result, len(jobs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, job := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, job Job) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			start := time.Now()
+			err := backend.CloneOrPull(job.Options)
+			results[i] = Result{Name: job.Name, Err: err, Duration: time.Since(start)}
+		}(i, job)
+	}
+	wg.Wait()
+	return results
+}
+
+// RunAll runs every job against backend, at most concurrency at a time, and
+// returns a combined error listing every repo that failed.
+func RunAll(backend Backend, jobs []Job, concurrency int) error {
+	results := RunAllResults(backend, jobs, concurrency)
+
+	var failures []string
+	for _, r := range results {
+		if r.Err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", r.Name, r.Err))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d repositories failed:\n%s", len(failures), len(jobs), strings.Join(failures, "\n"))
+}
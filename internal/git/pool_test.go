@@ -0,0 +1,122 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBackend fails CloneOrPull for any Options.TargetPath in failPaths,
+// and tracks how many calls are in flight at once.
+type fakeBackend struct {
+	failPaths map[string]bool
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (b *fakeBackend) CloneOrPull(opts Options) error {
+	b.mu.Lock()
+	b.inFlight++
+	if b.inFlight > b.maxInFlight {
+		b.maxInFlight = b.inFlight
+	}
+	b.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	b.mu.Lock()
+	b.inFlight--
+	b.mu.Unlock()
+
+	if b.failPaths[opts.TargetPath] {
+		return fmt.Errorf("simulated failure for %s", opts.TargetPath)
+	}
+	return nil
+}
+
+func TestRunAllResultsPreservesOrderAndReportsErrors(t *testing.T) {
+	backend := &fakeBackend{failPaths: map[string]bool{"b": true}}
+	jobs := []Job{
+		{Name: "a", Options: Options{TargetPath: "a"}},
+		{Name: "b", Options: Options{TargetPath: "b"}},
+		{Name: "c", Options: Options{TargetPath: "c"}},
+	}
+
+	results := RunAllResults(backend, jobs, 2)
+
+	if len(results) != len(jobs) {
+		t.Fatalf("got %d results, want %d", len(results), len(jobs))
+	}
+	for i, want := range []string{"a", "b", "c"} {
+		if results[i].Name != want {
+			t.Errorf("results[%d].Name = %q, want %q", i, results[i].Name, want)
+		}
+	}
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("expected a and c to succeed, got errs %v, %v", results[0].Err, results[2].Err)
+	}
+	if results[1].Err == nil {
+		t.Errorf("expected b to fail, got nil error")
+	}
+}
+
+func TestRunAllResultsRespectsConcurrencyLimit(t *testing.T) {
+	backend := &fakeBackend{}
+	var jobs []Job
+	for i := 0; i < 10; i++ {
+		jobs = append(jobs, Job{Name: fmt.Sprintf("job-%d", i), Options: Options{TargetPath: fmt.Sprintf("path-%d", i)}})
+	}
+
+	RunAllResults(backend, jobs, 3)
+
+	if backend.maxInFlight > 3 {
+		t.Errorf("max concurrent CloneOrPull calls = %d, want <= 3", backend.maxInFlight)
+	}
+}
+
+func TestRunAllResultsTreatsNonPositiveConcurrencyAsOne(t *testing.T) {
+	backend := &fakeBackend{}
+	var jobs []Job
+	for i := 0; i < 5; i++ {
+		jobs = append(jobs, Job{Name: fmt.Sprintf("job-%d", i), Options: Options{TargetPath: fmt.Sprintf("path-%d", i)}})
+	}
+
+	RunAllResults(backend, jobs, 0)
+
+	if backend.maxInFlight > 1 {
+		t.Errorf("max concurrent CloneOrPull calls = %d, want 1 for concurrency <= 0", backend.maxInFlight)
+	}
+}
+
+func TestRunAllAggregatesFailures(t *testing.T) {
+	backend := &fakeBackend{failPaths: map[string]bool{"a": true, "c": true}}
+	jobs := []Job{
+		{Name: "a", Options: Options{TargetPath: "a"}},
+		{Name: "b", Options: Options{TargetPath: "b"}},
+		{Name: "c", Options: Options{TargetPath: "c"}},
+	}
+
+	err := RunAll(backend, jobs, 2)
+	if err == nil {
+		t.Fatal("RunAll() = nil, want an aggregated error")
+	}
+	if !strings.Contains(err.Error(), "2 of 3") {
+		t.Errorf("error = %q, want it to mention 2 of 3 failures", err.Error())
+	}
+	if !strings.Contains(err.Error(), "a:") || !strings.Contains(err.Error(), "c:") {
+		t.Errorf("error = %q, want both failing job names", err.Error())
+	}
+}
+
+func TestRunAllNoFailures(t *testing.T) {
+	backend := &fakeBackend{}
+	jobs := []Job{{Name: "a", Options: Options{TargetPath: "a"}}}
+
+	if err := RunAll(backend, jobs, 1); err != nil {
+		t.Errorf("RunAll() = %v, want nil", err)
+	}
+}
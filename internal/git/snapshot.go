@@ -0,0 +1,45 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// PruneSnapshots keeps only the keep most recent unix-timestamp
+// subdirectories of repoDir (as created for --keep snapshots), removing the
+// rest. keep <= 0 is a no-op.
+func PruneSnapshots(repoDir string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(repoDir)
+	if err != nil {
+		return err
+	}
+
+	var timestamps []int64
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		ts, err := strconv.ParseInt(e.Name(), 10, 64)
+		if err != nil {
+			continue
+		}
+		timestamps = append(timestamps, ts)
+	}
+	if len(timestamps) <= keep {
+		return nil
+	}
+
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] > timestamps[j] })
+	for _, ts := range timestamps[keep:] {
+		if err := os.RemoveAll(filepath.Join(repoDir, strconv.FormatInt(ts, 10))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func mkSnapshotDirs(t *testing.T, repoDir string, timestamps ...string) {
+	t.Helper()
+	for _, ts := range timestamps {
+		if err := os.Mkdir(filepath.Join(repoDir, ts), 0755); err != nil {
+			t.Fatalf("Mkdir(%s): %v", ts, err)
+		}
+	}
+}
+
+func remainingSnapshots(t *testing.T, repoDir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(repoDir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestPruneSnapshotsKeepsNewestN(t *testing.T) {
+	repoDir := t.TempDir()
+	mkSnapshotDirs(t, repoDir, "100", "200", "300", "400")
+
+	if err := PruneSnapshots(repoDir, 2); err != nil {
+		t.Fatalf("PruneSnapshots: %v", err)
+	}
+
+	got := remainingSnapshots(t, repoDir)
+	want := map[string]bool{"300": true, "400": true}
+	if len(got) != len(want) {
+		t.Fatalf("remaining = %v, want exactly %v", got, want)
+	}
+	for _, name := range got {
+		if !want[name] {
+			t.Errorf("unexpected snapshot survived prune: %s", name)
+		}
+	}
+}
+
+func TestPruneSnapshotsExactlyAtKeepBoundaryIsNoop(t *testing.T) {
+	repoDir := t.TempDir()
+	mkSnapshotDirs(t, repoDir, "100", "200")
+
+	if err := PruneSnapshots(repoDir, 2); err != nil {
+		t.Fatalf("PruneSnapshots: %v", err)
+	}
+
+	got := remainingSnapshots(t, repoDir)
+	if len(got) != 2 {
+		t.Errorf("remaining = %v, want both snapshots kept when count == keep", got)
+	}
+}
+
+func TestPruneSnapshotsUnderKeepBoundaryIsNoop(t *testing.T) {
+	repoDir := t.TempDir()
+	mkSnapshotDirs(t, repoDir, "100")
+
+	if err := PruneSnapshots(repoDir, 2); err != nil {
+		t.Fatalf("PruneSnapshots: %v", err)
+	}
+
+	got := remainingSnapshots(t, repoDir)
+	if len(got) != 1 {
+		t.Errorf("remaining = %v, want the single snapshot kept when count < keep", got)
+	}
+}
+
+func TestPruneSnapshotsKeepZeroIsNoop(t *testing.T) {
+	repoDir := t.TempDir()
+	mkSnapshotDirs(t, repoDir, "100", "200", "300")
+
+	if err := PruneSnapshots(repoDir, 0); err != nil {
+		t.Fatalf("PruneSnapshots: %v", err)
+	}
+
+	got := remainingSnapshots(t, repoDir)
+	if len(got) != 3 {
+		t.Errorf("remaining = %v, want keep<=0 to leave everything", got)
+	}
+}
+
+func TestPruneSnapshotsIgnoresNonTimestampEntries(t *testing.T) {
+	repoDir := t.TempDir()
+	mkSnapshotDirs(t, repoDir, "100", "200", "not-a-timestamp")
+	if err := os.WriteFile(filepath.Join(repoDir, "300"), []byte("not a dir"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if err := PruneSnapshots(repoDir, 1); err != nil {
+		t.Fatalf("PruneSnapshots: %v", err)
+	}
+
+	got := remainingSnapshots(t, repoDir)
+	want := map[string]bool{"200": true, "not-a-timestamp": true, "300": true}
+	if len(got) != len(want) {
+		t.Fatalf("remaining = %v, want exactly %v", got, want)
+	}
+	for _, name := range got {
+		if !want[name] {
+			t.Errorf("unexpected entry: %s", name)
+		}
+	}
+}
@@ -1,24 +1,27 @@
 package load
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strings"
 
+	"github.com/google/go-github/v71/github"
 	"github.com/ossf/si-tooling/v2/si"
 )
 
 // LoadSecurityInsights loads SecurityInsights from a local file path or HTTP(S) URL.
 // If source is a path that exists as a file, it reads with os.ReadFile and calls si.Load.
-// If source looks like http:// or https://, it GETs the URL and calls si.Load.
-func LoadSecurityInsights(source string) (*si.SecurityInsights, error) {
+// If source looks like http:// or https://, it GETs the URL and calls si.Load. token, if
+// non-empty, is sent as a GitHub-style "Authorization: token ..." header for private URLs.
+func LoadSecurityInsights(source, token string) (*si.SecurityInsights, error) {
 	var contents []byte
 	var err error
 
 	if isURL(source) {
-		contents, err = fetchURL(source)
+		contents, err = fetchURL(source, token)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch URL: %w", err)
 		}
@@ -40,8 +43,15 @@ func isURL(s string) bool {
 	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
 }
 
-func fetchURL(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+func fetchURL(url, token string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -52,15 +62,33 @@ func fetchURL(url string) ([]byte, error) {
 	return io.ReadAll(resp.Body)
 }
 
-// LoadSecurityInsightsFromGitHub loads SecurityInsights from a public GitHub repository
-// using si.Read. path defaults to si.SecurityInsightsFilename if empty.
-func LoadSecurityInsightsFromGitHub(owner, repo, path string) (*si.SecurityInsights, error) {
+// LoadSecurityInsightsFromGitHub loads SecurityInsights from a GitHub
+// repository via the GitHub contents API. path defaults to
+// si.SecurityInsightsFilename if empty. token, if non-empty, authenticates
+// the request, so this also works for private repositories; si.Read (which
+// this used to delegate to) has no such parameter and only ever reaches
+// public repos.
+func LoadSecurityInsightsFromGitHub(owner, repo, path, token string) (*si.SecurityInsights, error) {
 	if path == "" {
 		path = si.SecurityInsightsFilename
 	}
-	insights, err := si.Read(owner, repo, path)
+
+	client := github.NewClient(http.DefaultClient)
+	if token != "" {
+		client = client.WithAuthToken(token)
+	}
+	content, _, _, err := client.Repositories.GetContents(context.Background(), owner, repo, path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read from GitHub: %w", err)
 	}
-	return &insights, nil
+	contents, err := content.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode GitHub content: %w", err)
+	}
+
+	insights, err := si.Load([]byte(contents))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load security insights: %w", err)
+	}
+	return insights, nil
 }
@@ -0,0 +1,57 @@
+package vcs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// azureDevOpsProvider handles https://dev.azure.com/org/project/_git/repo
+// and its SSH equivalent org@vs-ssh.visualstudio.com:v3/org/project/repo.
+// Owner is "org/project"; the "_git" path segment is implied and re-added
+// when rendering HTTPS, never stored on Ref.
+type azureDevOpsProvider struct{}
+
+func (azureDevOpsProvider) Name() string { return "azuredevops" }
+
+func (azureDevOpsProvider) Match(host string) bool {
+	return host == "dev.azure.com" || strings.HasSuffix(host, ".visualstudio.com")
+}
+
+func (azureDevOpsProvider) Parse(rawURL string) (*Ref, error) {
+	_, path, isSSH, err := splitURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	segments := strings.Split(path, "/")
+	if isSSH {
+		if len(segments) != 4 || segments[0] != "v3" {
+			return nil, fmt.Errorf("azure devops SSH URL must be v3/org/project/repo: %s", rawURL)
+		}
+		segments = segments[1:]
+	} else {
+		if len(segments) != 4 || segments[2] != "_git" {
+			return nil, fmt.Errorf("azure devops URL must be org/project/_git/repo: %s", rawURL)
+		}
+		segments = []string{segments[0], segments[1], segments[3]}
+	}
+	return &Ref{Host: "dev.azure.com", Owner: segments[0] + "/" + segments[1], Repo: segments[2]}, nil
+}
+
+func (azureDevOpsProvider) HTTPS(ref *Ref) string {
+	return fmt.Sprintf("https://dev.azure.com/%s/_git/%s", ref.Owner, ref.Repo)
+}
+
+func (azureDevOpsProvider) SSH(ref *Ref) string {
+	org := strings.SplitN(ref.Owner, "/", 2)[0]
+	return fmt.Sprintf("%s@vs-ssh.visualstudio.com:v3/%s/%s", org, ref.Owner, ref.Repo)
+}
+
+// WithOwner substitutes only the org segment of Owner ("org/project"),
+// keeping the project the same, since a fork under a different org still
+// lives in that org's own copy of the same project.
+func (azureDevOpsProvider) WithOwner(ref *Ref, owner string) *Ref {
+	_, project, _ := strings.Cut(ref.Owner, "/")
+	clone := *ref
+	clone.Owner = owner + "/" + project
+	return &clone
+}
@@ -0,0 +1,36 @@
+package vcs
+
+import (
+	"fmt"
+	"strings"
+)
+
+type bitbucketProvider struct{}
+
+func (bitbucketProvider) Name() string { return "bitbucket" }
+
+func (bitbucketProvider) Match(host string) bool { return host == "bitbucket.org" }
+
+func (bitbucketProvider) Parse(rawURL string) (*Ref, error) {
+	_, path, _, err := splitURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("bitbucket URL has no owner/repo path: %s", rawURL)
+	}
+	return &Ref{Host: "bitbucket.org", Owner: parts[0], Repo: parts[1]}, nil
+}
+
+func (bitbucketProvider) HTTPS(ref *Ref) string {
+	return "https://bitbucket.org/" + ref.Owner + "/" + ref.Repo
+}
+
+func (bitbucketProvider) SSH(ref *Ref) string {
+	return "git@bitbucket.org:" + ref.Owner + "/" + ref.Repo + ".git"
+}
+
+func (bitbucketProvider) WithOwner(ref *Ref, owner string) *Ref {
+	return withOwner(ref, owner)
+}
@@ -0,0 +1,21 @@
+package vcs
+
+import "strings"
+
+// SplitFragment splits a Docker-style git remote URL of the form
+// "https://host/owner/repo.git#ref:subdir" into its base URL, ref, and
+// subdir. ref may be a branch, tag, or commit SHA; subdir selects a subtree
+// within the repo. Either or both may be absent: "...#ref" yields an empty
+// subdir, and a URL with no "#" yields both empty.
+func SplitFragment(rawURL string) (base, ref, subdir string) {
+	idx := strings.LastIndex(rawURL, "#")
+	if idx < 0 {
+		return rawURL, "", ""
+	}
+	base = rawURL[:idx]
+	fragment := rawURL[idx+1:]
+	if ci := strings.Index(fragment, ":"); ci >= 0 {
+		return base, fragment[:ci], fragment[ci+1:]
+	}
+	return base, fragment, ""
+}
@@ -0,0 +1,38 @@
+package vcs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// genericProvider is the fallback for hosts with no specific registration:
+// it assumes GitHub-like owner/repo semantics.
+type genericProvider struct{}
+
+func (genericProvider) Name() string { return "generic" }
+
+func (genericProvider) Match(host string) bool { return true }
+
+func (genericProvider) Parse(rawURL string) (*Ref, error) {
+	host, path, _, err := splitURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("URL has no owner/repo path: %s", rawURL)
+	}
+	return &Ref{Host: host, Owner: parts[0], Repo: parts[1]}, nil
+}
+
+func (genericProvider) HTTPS(ref *Ref) string {
+	return "https://" + ref.Host + "/" + ref.Owner + "/" + ref.Repo
+}
+
+func (genericProvider) SSH(ref *Ref) string {
+	return "git@" + ref.Host + ":" + ref.Owner + "/" + ref.Repo + ".git"
+}
+
+func (genericProvider) WithOwner(ref *Ref, owner string) *Ref {
+	return withOwner(ref, owner)
+}
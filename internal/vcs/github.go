@@ -0,0 +1,36 @@
+package vcs
+
+import (
+	"fmt"
+	"strings"
+)
+
+type githubProvider struct{}
+
+func (githubProvider) Name() string { return "github" }
+
+func (githubProvider) Match(host string) bool { return host == "github.com" }
+
+func (githubProvider) Parse(rawURL string) (*Ref, error) {
+	_, path, _, err := splitURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("github URL has no owner/repo path: %s", rawURL)
+	}
+	return &Ref{Host: "github.com", Owner: parts[0], Repo: parts[1]}, nil
+}
+
+func (githubProvider) HTTPS(ref *Ref) string {
+	return "https://github.com/" + ref.Owner + "/" + ref.Repo
+}
+
+func (githubProvider) SSH(ref *Ref) string {
+	return "git@github.com:" + ref.Owner + "/" + ref.Repo + ".git"
+}
+
+func (githubProvider) WithOwner(ref *Ref, owner string) *Ref {
+	return withOwner(ref, owner)
+}
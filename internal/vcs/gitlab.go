@@ -0,0 +1,40 @@
+package vcs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// gitlabProvider handles GitLab's arbitrary-depth subgroups: everything in
+// the path before the final segment is the (sub)group path, the Owner.
+type gitlabProvider struct{}
+
+func (gitlabProvider) Name() string { return "gitlab" }
+
+func (gitlabProvider) Match(host string) bool { return host == "gitlab.com" }
+
+func (gitlabProvider) Parse(rawURL string) (*Ref, error) {
+	_, path, _, err := splitURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	segments := strings.Split(path, "/")
+	if len(segments) < 2 || segments[0] == "" || segments[len(segments)-1] == "" {
+		return nil, fmt.Errorf("gitlab URL has no group/repo path: %s", rawURL)
+	}
+	owner := strings.Join(segments[:len(segments)-1], "/")
+	repo := segments[len(segments)-1]
+	return &Ref{Host: "gitlab.com", Owner: owner, Repo: repo}, nil
+}
+
+func (gitlabProvider) HTTPS(ref *Ref) string {
+	return "https://gitlab.com/" + ref.Owner + "/" + ref.Repo
+}
+
+func (gitlabProvider) SSH(ref *Ref) string {
+	return "git@gitlab.com:" + ref.Owner + "/" + ref.Repo + ".git"
+}
+
+func (gitlabProvider) WithOwner(ref *Ref, owner string) *Ref {
+	return withOwner(ref, owner)
+}
@@ -0,0 +1,41 @@
+package vcs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sourceHutProvider handles git.sr.ht/~user/repo, where the "~" is part of
+// the owner rather than a generic path separator.
+type sourceHutProvider struct{}
+
+func (sourceHutProvider) Name() string { return "sourcehut" }
+
+func (sourceHutProvider) Match(host string) bool { return host == "git.sr.ht" }
+
+func (sourceHutProvider) Parse(rawURL string) (*Ref, error) {
+	_, path, _, err := splitURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) < 2 || !strings.HasPrefix(parts[0], "~") || parts[1] == "" {
+		return nil, fmt.Errorf("sourcehut URL must be ~user/repo: %s", rawURL)
+	}
+	return &Ref{Host: "git.sr.ht", Owner: parts[0], Repo: parts[1]}, nil
+}
+
+func (sourceHutProvider) HTTPS(ref *Ref) string {
+	return "https://git.sr.ht/" + ref.Owner + "/" + ref.Repo
+}
+
+func (sourceHutProvider) SSH(ref *Ref) string {
+	return "git@git.sr.ht:" + ref.Owner + "/" + ref.Repo
+}
+
+// WithOwner re-adds the mandatory "~" that plain usernames don't carry.
+func (sourceHutProvider) WithOwner(ref *Ref, owner string) *Ref {
+	clone := *ref
+	clone.Owner = "~" + strings.TrimPrefix(owner, "~")
+	return &clone
+}
@@ -0,0 +1,130 @@
+// Package vcs normalizes repository URLs across forges. Each supported host
+// is registered as a Provider that knows its own owner/repo semantics (plain
+// owner/repo, arbitrary-depth subgroups, org/project/_git/repo, ~user/repo,
+// ...) and how to render HTTPS and SSH clone URLs for it. Hosts with no
+// specific Provider fall back to GitHub-like owner/repo semantics.
+package vcs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Ref is a repository reference parsed out of a URL.
+type Ref struct {
+	// Host is the forge's canonical HTTPS host, e.g. "github.com" or
+	// "dev.azure.com" (even when parsed from an SSH URL on a different host).
+	Host string
+	// Owner is the namespace the repo lives under. For most providers this
+	// is a single user or group; GitLab allows arbitrary-depth subgroups
+	// joined by "/", and Azure DevOps uses "org/project".
+	Owner string
+	// Repo is the repository name, without a ".git" suffix.
+	Repo string
+}
+
+// Provider recognizes, parses, and renders URLs for one forge.
+type Provider interface {
+	// Name identifies the provider for error messages, e.g. "github".
+	Name() string
+	// Match reports whether this provider owns host, as it appears in an
+	// https:// URL or as an SSH host.
+	Match(host string) bool
+	// Parse extracts a Ref from a raw repository URL (https://, SSH
+	// user@host:path, or bare host:path) whose host Match has claimed.
+	Parse(rawURL string) (*Ref, error)
+	// HTTPS renders ref as an https:// clone URL.
+	HTTPS(ref *Ref) string
+	// SSH renders ref as an SSH clone URL.
+	SSH(ref *Ref) string
+	// WithOwner returns a copy of ref under a different owner, for deriving
+	// a fork URL. Most providers treat Owner as a single flat namespace and
+	// substitute it outright, but providers whose Owner packs in more
+	// structure (Azure DevOps' "org/project", SourceHut's "~user") override
+	// this to substitute only the user-owned part.
+	WithOwner(ref *Ref, owner string) *Ref
+}
+
+var providers []Provider
+
+// Register adds a provider to the registry, to be tried ahead of the
+// generic fallback. Callers can use this to add custom on-prem hosts, e.g.
+// a self-hosted GitLab instance, by registering a Provider whose Match
+// recognizes that host.
+func Register(p Provider) {
+	providers = append(providers, p)
+}
+
+// Lookup returns the provider that owns host, or the generic GitHub-like
+// fallback if none of the registered providers claim it.
+func Lookup(host string) Provider {
+	host = strings.ToLower(host)
+	for _, p := range providers {
+		if p.Match(host) {
+			return p
+		}
+	}
+	return genericProvider{}
+}
+
+func init() {
+	Register(githubProvider{})
+	Register(gitlabProvider{})
+	Register(bitbucketProvider{})
+	Register(azureDevOpsProvider{})
+	Register(sourceHutProvider{})
+}
+
+// Parse detects the provider for rawURL by host and parses it into a Ref.
+func Parse(rawURL string) (*Ref, Provider, error) {
+	host, _, _, err := splitURL(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := Lookup(host)
+	ref, err := p.Parse(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ref, p, nil
+}
+
+// withOwner returns a copy of ref with Owner replaced outright by owner.
+// It implements the flat-namespace case of Provider.WithOwner; providers
+// whose Owner carries more structure than a single user/group define their
+// own WithOwner instead of using this.
+func withOwner(ref *Ref, owner string) *Ref {
+	clone := *ref
+	clone.Owner = owner
+	return &clone
+}
+
+// splitURL extracts host and path from a repository URL in https://, SSH
+// (user@host:path), or bare host:path form. path has no leading slash and
+// no trailing ".git".
+func splitURL(rawURL string) (host, path string, isSSH bool, err error) {
+	raw := strings.TrimSpace(rawURL)
+
+	if strings.HasPrefix(raw, "https://") || strings.HasPrefix(raw, "http://") {
+		idx := strings.Index(raw, "://")
+		rest := raw[idx+3:]
+		slash := strings.Index(rest, "/")
+		if slash < 0 {
+			return "", "", false, fmt.Errorf("URL has no path: %s", rawURL)
+		}
+		return rest[:slash], strings.TrimSuffix(strings.Trim(rest[slash:], "/"), ".git"), false, nil
+	}
+
+	if idx := strings.Index(raw, "@"); idx >= 0 {
+		rest := raw[idx+1:]
+		if ci := strings.Index(rest, ":"); ci > 0 {
+			return rest[:ci], strings.TrimSuffix(strings.TrimPrefix(rest[ci+1:], "/"), ".git"), true, nil
+		}
+	}
+
+	if idx := strings.Index(raw, ":"); idx > 0 && !strings.Contains(raw[:idx], "/") {
+		return raw[:idx], strings.TrimSuffix(strings.TrimPrefix(raw[idx+1:], "/"), ".git"), true, nil
+	}
+
+	return "", "", false, fmt.Errorf("cannot parse repository URL: %s", rawURL)
+}
@@ -0,0 +1,146 @@
+package vcs
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name      string
+		url       string
+		wantHost  string
+		wantOwner string
+		wantRepo  string
+		wantErr   bool
+	}{
+		{"github https", "https://github.com/owner/repo.git", "github.com", "owner", "repo", false},
+		{"github ssh", "git@github.com:owner/repo.git", "github.com", "owner", "repo", false},
+		{"gitlab top-level", "https://gitlab.com/owner/repo.git", "gitlab.com", "owner", "repo", false},
+		{"gitlab subgroup", "https://gitlab.com/group/subgroup/repo", "gitlab.com", "group/subgroup", "repo", false},
+		{"gitlab arbitrary-depth subgroup", "https://gitlab.com/a/b/c/d/repo.git", "gitlab.com", "a/b/c/d", "repo", false},
+		{"bitbucket https", "https://bitbucket.org/owner/repo.git", "bitbucket.org", "owner", "repo", false},
+		{"bitbucket ssh", "git@bitbucket.org:owner/repo.git", "bitbucket.org", "owner", "repo", false},
+		{"azure devops https", "https://dev.azure.com/org/project/_git/repo", "dev.azure.com", "org/project", "repo", false},
+		{"azure devops visualstudio https", "https://org.visualstudio.com/project/_git/repo", "", "", "", true},
+		{"azure devops ssh", "org@vs-ssh.visualstudio.com:v3/org/project/repo", "dev.azure.com", "org/project", "repo", false},
+		{"azure devops https missing _git", "https://dev.azure.com/org/project/repo", "", "", "", true},
+		{"azure devops ssh missing v3", "org@vs-ssh.visualstudio.com:org/project/repo", "", "", "", true},
+		{"sourcehut", "https://git.sr.ht/~user/repo", "git.sr.ht", "~user", "repo", false},
+		{"sourcehut missing tilde", "https://git.sr.ht/user/repo", "", "", "", true},
+		{"generic fallback", "https://example.com/owner/repo.git", "example.com", "owner", "repo", false},
+		{"generic fallback missing repo", "https://example.com/owner", "", "", "", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ref, _, err := Parse(c.url)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %+v, want an error", c.url, ref)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): unexpected error: %v", c.url, err)
+			}
+			if ref.Host != c.wantHost || ref.Owner != c.wantOwner || ref.Repo != c.wantRepo {
+				t.Errorf("Parse(%q) = %+v, want {Host:%s Owner:%s Repo:%s}", c.url, ref, c.wantHost, c.wantOwner, c.wantRepo)
+			}
+		})
+	}
+}
+
+func TestRenderRoundTrip(t *testing.T) {
+	cases := []struct {
+		name      string
+		url       string
+		wantHTTPS string
+		wantSSH   string
+	}{
+		{"github", "https://github.com/owner/repo.git", "https://github.com/owner/repo", "git@github.com:owner/repo.git"},
+		{"gitlab subgroup", "https://gitlab.com/group/subgroup/repo", "https://gitlab.com/group/subgroup/repo", "git@gitlab.com:group/subgroup/repo.git"},
+		{"bitbucket", "https://bitbucket.org/owner/repo", "https://bitbucket.org/owner/repo", "git@bitbucket.org:owner/repo.git"},
+		{"azure devops", "https://dev.azure.com/org/project/_git/repo", "https://dev.azure.com/org/project/_git/repo", "org@vs-ssh.visualstudio.com:v3/org/project/repo"},
+		{"sourcehut", "https://git.sr.ht/~user/repo", "https://git.sr.ht/~user/repo", "git@git.sr.ht:~user/repo"},
+		{"generic", "https://example.com/owner/repo", "https://example.com/owner/repo", "git@example.com:owner/repo.git"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ref, p, err := Parse(c.url)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", c.url, err)
+			}
+			if got := p.HTTPS(ref); got != c.wantHTTPS {
+				t.Errorf("HTTPS(%q) = %q, want %q", c.url, got, c.wantHTTPS)
+			}
+			if got := p.SSH(ref); got != c.wantSSH {
+				t.Errorf("SSH(%q) = %q, want %q", c.url, got, c.wantSSH)
+			}
+		})
+	}
+}
+
+func TestWithOwner(t *testing.T) {
+	ref := &Ref{Host: "github.com", Owner: "upstream", Repo: "repo"}
+	fork := githubProvider{}.WithOwner(ref, "me")
+
+	if fork.Owner != "me" {
+		t.Errorf("WithOwner(ref, %q).Owner = %q, want %q", "me", fork.Owner, "me")
+	}
+	if ref.Owner != "upstream" {
+		t.Errorf("WithOwner mutated the original ref: Owner = %q, want %q", ref.Owner, "upstream")
+	}
+}
+
+func TestWithOwnerPerProvider(t *testing.T) {
+	cases := []struct {
+		name      string
+		provider  Provider
+		ref       *Ref
+		newOwner  string
+		wantOwner string
+	}{
+		{"github flat", githubProvider{}, &Ref{Host: "github.com", Owner: "upstream", Repo: "repo"}, "me", "me"},
+		{"gitlab subgroup path replaced wholesale", gitlabProvider{}, &Ref{Host: "gitlab.com", Owner: "a/b/c", Repo: "repo"}, "me", "me"},
+		{"azure devops keeps project", azureDevOpsProvider{}, &Ref{Host: "dev.azure.com", Owner: "org/project", Repo: "repo"}, "me", "me/project"},
+		{"sourcehut adds tilde", sourceHutProvider{}, &Ref{Host: "git.sr.ht", Owner: "~upstream", Repo: "repo"}, "me", "~me"},
+		{"sourcehut tolerates a tilde already on the new owner", sourceHutProvider{}, &Ref{Host: "git.sr.ht", Owner: "~upstream", Repo: "repo"}, "~me", "~me"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.provider.WithOwner(c.ref, c.newOwner)
+			if got.Owner != c.wantOwner {
+				t.Errorf("%s.WithOwner(%+v, %q).Owner = %q, want %q", c.provider.Name(), c.ref, c.newOwner, got.Owner, c.wantOwner)
+			}
+		})
+	}
+}
+
+func TestAzureDevOpsForkURLRoundTrip(t *testing.T) {
+	ref, p, err := Parse("https://dev.azure.com/org/project/_git/repo")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	fork := p.WithOwner(ref, "me")
+	if got, want := p.HTTPS(fork), "https://dev.azure.com/me/project/_git/repo"; got != want {
+		t.Errorf("HTTPS(fork) = %q, want %q", got, want)
+	}
+}
+
+func TestSourceHutForkURLRoundTrip(t *testing.T) {
+	ref, p, err := Parse("https://git.sr.ht/~upstream/repo")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	fork := p.WithOwner(ref, "me")
+	if got, want := p.HTTPS(fork), "https://git.sr.ht/~me/repo"; got != want {
+		t.Errorf("HTTPS(fork) = %q, want %q", got, want)
+	}
+}
+
+func TestLookupFallsBackToGeneric(t *testing.T) {
+	p := Lookup("git.example.org")
+	if p.Name() != "generic" {
+		t.Errorf("Lookup(%q).Name() = %q, want %q", "git.example.org", p.Name(), "generic")
+	}
+}
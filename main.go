@@ -1,31 +1,62 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/revanite-io/project-puller/internal/creds"
+	"github.com/revanite-io/project-puller/internal/git"
 	"github.com/revanite-io/project-puller/internal/load"
+	"github.com/revanite-io/project-puller/internal/vcs"
 	"github.com/ossf/si-tooling/v2/si"
 	"github.com/spf13/cobra"
 )
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		code := 1
+		var se *statusError
+		if errors.As(err, &se) {
+			code = se.exitCode
+		}
+		os.Exit(code)
 	}
 }
 
+// statusError carries a process exit code distinct from the default 1, so
+// --output-format=json runs can tell a CI pipeline whether every repo
+// failed (1) or only some did (2).
+type statusError struct {
+	err      error
+	exitCode int
+}
+
+func (e *statusError) Error() string { return e.err.Error() }
+func (e *statusError) Unwrap() error { return e.err }
+
 var (
-	source   string
-	github   string
-	dir      string
-	username string
-	ssh      bool
-	quiet    bool
+	source        string
+	github        string
+	dir           string
+	username      string
+	ssh           bool
+	quiet         bool
+	backend       string
+	jobs          int
+	token         string
+	tokenFromFile string
+	bare          bool
+	mirror        bool
+	structured    bool
+	keep          int
+	dryRun        bool
+	outputFormat  string
 )
 
 var rootCmd = &cobra.Command{
@@ -43,30 +74,54 @@ func init() {
 	rootCmd.Flags().StringVarP(&username, "username", "u", "", "Fork username; clone with remote upstream and add your fork as origin")
 	rootCmd.Flags().BoolVar(&ssh, "ssh", false, "Use SSH URLs for clone and remotes (default: HTTPS)")
 	rootCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Suppress git output")
+	rootCmd.Flags().StringVar(&backend, "backend", string(git.Exec), "Git backend to use: exec or go-git")
+	rootCmd.Flags().IntVar(&jobs, "jobs", 1, "Number of repositories to clone/pull concurrently")
+	rootCmd.Flags().StringVar(&token, "token", "", "GitHub token for private repos/files (skips credential prompting)")
+	rootCmd.Flags().StringVar(&tokenFromFile, "token-from-file", "", "Path to a file containing a GitHub token (skips credential prompting)")
+	rootCmd.Flags().BoolVar(&bare, "bare", false, "Clone as a bare repository (repo.git)")
+	rootCmd.Flags().BoolVar(&mirror, "mirror", false, "Clone as a mirror (all refs); pulls run git remote update")
+	rootCmd.Flags().BoolVar(&structured, "structured", false, "Lay out repos as <output>/<host>/<owner>/<repo> instead of flat")
+	rootCmd.Flags().IntVar(&keep, "keep", 0, "Keep the N most recent timestamped snapshots per repo instead of pulling in place")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the git commands each repo would run, without running them")
+	rootCmd.Flags().StringVar(&outputFormat, "output-format", "text", "Result output format: text or json (json writes one object per repo to stdout; human logs go to stderr)")
 }
 
 func run(cmd *cobra.Command, args []string) error {
-	var insights *si.SecurityInsights
-	var err error
+	ghToken, err := nonInteractiveToken()
+	if err != nil {
+		return err
+	}
 
-	if github != "" {
-		owner, repo, path := parseGitHubFlag(github)
-		if owner == "" || repo == "" {
-			return fmt.Errorf("--github must be owner/repo or owner/repo/path")
-		}
-		insights, err = load.LoadSecurityInsightsFromGitHub(owner, repo, path)
-	} else {
-		src := source
-		if len(args) > 0 {
-			src = args[0]
-		}
-		if src == "" && source != "" {
-			src = source
+	src := source
+	if len(args) > 0 {
+		src = args[0]
+	}
+
+	loadInsights := func(t string) (*si.SecurityInsights, error) {
+		if github != "" {
+			owner, repo, path := parseGitHubFlag(github)
+			if owner == "" || repo == "" {
+				return nil, fmt.Errorf("--github must be owner/repo or owner/repo/path")
+			}
+			return load.LoadSecurityInsightsFromGitHub(owner, repo, path, t)
 		}
 		if src == "" {
-			return fmt.Errorf("provide a file path, URL, or use --g owner/repo")
+			return nil, fmt.Errorf("provide a file path, URL, or use --g owner/repo")
+		}
+		return load.LoadSecurityInsights(src, t)
+	}
+
+	insights, err := loadInsights(ghToken)
+	if err != nil && ghToken == "" && creds.IsTerminal() {
+		// The load may have failed because the source needs credentials we
+		// haven't prompted for yet; retry once with an interactively
+		// supplied token rather than always prompting up front.
+		if promptedToken, promptErr := promptForToken(); promptErr == nil {
+			if retried, retryErr := loadInsights(promptedToken); retryErr == nil {
+				insights, err = retried, nil
+				ghToken = promptedToken
+			}
 		}
-		insights, err = load.LoadSecurityInsights(src)
 	}
 	if err != nil {
 		return err
@@ -74,34 +129,318 @@ func run(cmd *cobra.Command, args []string) error {
 	if insights.Project == nil || len(insights.Project.Repositories) == 0 {
 		return fmt.Errorf("security insights file has no project or repositories listed")
 	}
+	if bare && mirror {
+		return fmt.Errorf("--bare and --mirror are mutually exclusive")
+	}
 
 	if dir == "" && insights.Project.Name != "" {
 		dir = insights.Project.Name
 	}
 
+	if !dryRun {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create target directory %s: %w", dir, err)
+		}
+	}
 
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create target directory %s: %w", dir, err)
+	backendImpl, err := git.New(git.Name(backend), quiet)
+	if err != nil {
+		return err
 	}
 
+	repos := insights.Project.Repositories
+	snapshotTS := time.Now().Unix()
 	usedNames := make(map[string]bool)
-	for _, r := range insights.Project.Repositories {
-		repoURL := string(r.Url)
-		effectiveURL, err := normalizeRepoURL(repoURL, ssh)
+	var jobList []git.Job
+	var snapshotBases []string
+	var buildFailures []string
+	for _, r := range repos {
+		repoURL, ref, subdir := vcs.SplitFragment(string(r.Url))
+
+		job, basePath, err := buildJob(r, repoURL, ref, subdir, ghToken, usedNames, snapshotTS)
 		if err != nil {
-			return fmt.Errorf("repo %s: %w", repoURL, err)
+			buildFailures = append(buildFailures, fmt.Sprintf("%s: %v", repoURL, err))
+			continue
 		}
-		dirName := repoDirName(r, repoURL, usedNames)
-		usedNames[dirName] = true
-		targetPath := filepath.Join(dir, dirName)
+		if !dryRun {
+			if err := os.MkdirAll(filepath.Dir(job.TargetPath), 0755); err != nil {
+				buildFailures = append(buildFailures, fmt.Sprintf("%s: failed to create target directory: %v", repoURL, err))
+				continue
+			}
+		}
+		if keep > 0 {
+			snapshotBases = append(snapshotBases, basePath)
+		}
+		jobList = append(jobList, job)
+	}
 
-		if err := cloneOrPull(targetPath, effectiveURL, username); err != nil {
-			return fmt.Errorf("git failed for %s: %w", dirName, err)
+	if dryRun {
+		if err := printPlans(jobList); err != nil {
+			return err
+		}
+		return buildFailuresErr(buildFailures, len(repos))
+	}
+
+	var runErr error
+	switch {
+	case outputFormat == "json":
+		runErr = runAllJSON(backendImpl, jobList, concurrency(), buildFailures, len(repos))
+	case len(buildFailures) == 0:
+		runErr = git.RunAll(backendImpl, jobList, concurrency())
+	default:
+		runErr = runAllWithBuildFailures(backendImpl, jobList, concurrency(), buildFailures, len(repos))
+	}
+
+	for _, basePath := range snapshotBases {
+		if err := git.PruneSnapshots(basePath, keep); err != nil {
+			fmt.Fprintf(os.Stderr, "prune %s: %v\n", basePath, err)
+		}
+	}
+
+	return runErr
+}
+
+// buildJob resolves everything main needs to clone or pull one repository
+// into a git.Job: its fork-aware target path (basePath, before any --keep
+// timestamp subdir is appended) and its Options. Errors here are per-repo
+// (a URL its host's provider cannot parse) and must not abort the rest of the
+// batch, so callers collect them instead of returning early.
+func buildJob(r si.ProjectRepository, repoURL, ref, subdir, ghToken string, usedNames map[string]bool, snapshotTS int64) (job git.Job, basePath string, err error) {
+	effectiveURL, err := normalizeRepoURL(repoURL, ssh)
+	if err != nil {
+		return git.Job{}, "", err
+	}
+
+	// ghToken is a GitHub credential; only send it to github.com, not to
+	// whatever other forge this particular repo happens to live on.
+	repoToken := ""
+	if parsedRef, _, err := vcs.Parse(repoURL); err == nil && parsedRef.Host == "github.com" {
+		repoToken = ghToken
+	}
+
+	relPath, err := repoRelPath(r, repoURL, usedNames)
+	if err != nil {
+		return git.Job{}, "", err
+	}
+	basePath = filepath.Join(dir, relPath)
+	targetPath := basePath
+	if keep > 0 {
+		targetPath = filepath.Join(basePath, strconv.FormatInt(snapshotTS, 10))
+	}
+
+	forkURL := ""
+	if username != "" {
+		forkURL, err = forkURLFromUpstream(repoURL, username, ssh)
+		if err != nil {
+			return git.Job{}, "", err
+		}
+	}
+
+	return git.Job{
+		Name: relPath,
+		Options: git.Options{
+			TargetPath: targetPath,
+			RepoURL:    effectiveURL,
+			ForkURL:    forkURL,
+			Ref:        ref,
+			Subdir:     subdir,
+			Auth:       git.Auth{Token: repoToken},
+			Bare:       bare,
+			Mirror:     mirror,
+		},
+	}, basePath, nil
+}
+
+// buildFailuresErr reports repos that failed to resolve into a job at all
+// (as opposed to failing to clone/pull), for callers like --dry-run that
+// never reach git.RunAll.
+func buildFailuresErr(buildFailures []string, total int) error {
+	if len(buildFailures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d repositories could not be resolved:\n%s", len(buildFailures), total, strings.Join(buildFailures, "\n"))
+}
+
+// runAllWithBuildFailures runs jobList like git.RunAll, then folds in repos
+// that failed before a Job could even be built, so one bad URL is reported
+// alongside any clone/pull failures instead of being silently dropped.
+func runAllWithBuildFailures(backendImpl git.Backend, jobList []git.Job, conc int, buildFailures []string, total int) error {
+	results := git.RunAllResults(backendImpl, jobList, conc)
+
+	msgs := append([]string{}, buildFailures...)
+	failed := len(buildFailures)
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			msgs = append(msgs, fmt.Sprintf("%s: %v", r.Name, r.Err))
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d repositories failed:\n%s", failed, total, strings.Join(msgs, "\n"))
+}
+
+// repoStatus is one line of --output-format=json output, describing a
+// single repo's planned or actual outcome.
+type repoStatus struct {
+	Name        string `json:"name"`
+	UpstreamURL string `json:"upstream_url"`
+	ForkURL     string `json:"fork_url"`
+	TargetPath  string `json:"target_path"`
+	Action      string `json:"action"`
+	Status      string `json:"status"`
+	DurationMs  int64  `json:"duration_ms"`
+	Error       string `json:"error"`
+}
+
+// printPlans implements --dry-run: it reports what each job would do
+// without touching the filesystem or network. Under --output-format=json
+// it writes one repoStatus object per repo to stdout with status
+// "planned"; otherwise it prints the planned git commands to stdout, with
+// the repo name as a stderr header, matching the exec backend's own
+// human/machine stream split.
+func printPlans(jobList []git.Job) error {
+	for _, job := range jobList {
+		plan := git.DescribePlan(job.Options)
+		if outputFormat == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			if err := enc.Encode(repoStatus{
+				Name:        job.Name,
+				UpstreamURL: job.Options.RepoURL,
+				ForkURL:     job.Options.ForkURL,
+				TargetPath:  job.Options.TargetPath,
+				Action:      plan.Action,
+				Status:      "planned",
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "%s (%s):\n", job.Name, plan.Action)
+		for _, c := range plan.Commands {
+			fmt.Fprintf(os.Stdout, "  %s\n", c)
 		}
 	}
 	return nil
 }
 
+// runAllJSON runs jobList like git.RunAll, but writes one repoStatus object
+// per repo to stdout once every job has finished, in jobList order, first
+// emitting one for each repo in buildFailures (which never made it into
+// jobList), and returns a
+// *statusError carrying an exit code that distinguishes every repo failing
+// (1) from only some of them failing (2), for CI pipelines that check $?.
+func runAllJSON(backendImpl git.Backend, jobList []git.Job, conc int, buildFailures []string, total int) error {
+	enc := json.NewEncoder(os.Stdout)
+	failed := 0
+	for _, msg := range buildFailures {
+		failed++
+		_ = enc.Encode(repoStatus{Action: "skipped", Status: "error", Error: msg})
+	}
+
+	plans := make([]git.Plan, len(jobList))
+	for i, job := range jobList {
+		plans[i] = git.DescribePlan(job.Options)
+	}
+
+	results := git.RunAllResults(backendImpl, jobList, conc)
+
+	for i, r := range results {
+		status, errMsg := "ok", ""
+		if r.Err != nil {
+			status, errMsg = "error", r.Err.Error()
+			failed++
+		}
+		_ = enc.Encode(repoStatus{
+			Name:        jobList[i].Name,
+			UpstreamURL: jobList[i].Options.RepoURL,
+			ForkURL:     jobList[i].Options.ForkURL,
+			TargetPath:  jobList[i].Options.TargetPath,
+			Action:      plans[i].Action,
+			Status:      status,
+			DurationMs:  r.Duration.Milliseconds(),
+			Error:       errMsg,
+		})
+	}
+
+	switch {
+	case failed == 0:
+		return nil
+	case failed == total:
+		return &statusError{err: fmt.Errorf("all %d repositories failed", failed), exitCode: 1}
+	default:
+		return &statusError{err: fmt.Errorf("%d of %d repositories failed", failed, total), exitCode: 2}
+	}
+}
+
+// repoRelPath returns the path of a repository relative to the output
+// directory: <host>/<owner>/<repo> under --structured, or the flat
+// deduplicated name repoDirName has always produced otherwise. The leaf
+// component gets a ".git" suffix under --bare or --mirror.
+func repoRelPath(r si.ProjectRepository, repoURL string, usedNames map[string]bool) (string, error) {
+	if structured {
+		ref, _, err := vcs.Parse(repoURL)
+		if err != nil {
+			return "", err
+		}
+		leaf := ref.Repo
+		if bare || mirror {
+			leaf += ".git"
+		}
+		return filepath.Join(ref.Host, ref.Owner, leaf), nil
+	}
+
+	leaf := repoDirName(r, repoURL, usedNames)
+	usedNames[leaf] = true
+	if bare || mirror {
+		leaf += ".git"
+	}
+	return leaf, nil
+}
+
+// nonInteractiveToken returns a GitHub token for private repos/files
+// without ever prompting: --token or --token-from-file when given
+// (bypassing discovery entirely, for CI), otherwise TokenForHost. Returns ""
+// if none are available; run only falls back to prompting after that empty
+// token actually fails to load something, so a plain invocation against
+// public repos never blocks on a credential prompt.
+func nonInteractiveToken() (string, error) {
+	if token != "" {
+		return token, nil
+	}
+	if tokenFromFile != "" {
+		data, err := os.ReadFile(tokenFromFile)
+		if err != nil {
+			return "", fmt.Errorf("read --token-from-file %s: %w", tokenFromFile, err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return creds.TokenForHost("github.com"), nil
+}
+
+// promptForToken interactively asks for a GitHub personal access token and
+// caches it in the credential store for next time.
+func promptForToken() (string, error) {
+	t, err := creds.PromptToken()
+	if err != nil {
+		return "", err
+	}
+	if store, err := creds.LoadStore(); err == nil {
+		_ = store.SetToken("github.com", t)
+	}
+	return t, nil
+}
+
+// concurrency returns the number of repositories to process at once: --jobs
+// when positive, otherwise 1 (the long-standing sequential default).
+func concurrency() int {
+	if jobs > 0 {
+		return jobs
+	}
+	return 1
+}
+
 func parseGitHubFlag(s string) (owner, repo, path string) {
 	parts := strings.SplitN(s, "/", 3)
 	if len(parts) >= 2 {
@@ -151,230 +490,33 @@ func lastPathComponent(url string) string {
 	return url
 }
 
-// normalizeRepoURL returns the repo URL in SSH or HTTPS form depending on useSSH.
+// normalizeRepoURL returns the repo URL in SSH or HTTPS form depending on
+// useSSH, dispatching to the vcs registry's provider for the repo's host.
 func normalizeRepoURL(repoURL string, useSSH bool) (string, error) {
-	if useSSH {
-		return repoURLToSSH(repoURL)
-	}
-	return repoURLToHTTPS(repoURL)
-}
-
-func repoURLToSSH(repoURL string) (string, error) {
-	repoURL = strings.TrimSpace(repoURL)
-	// Already SSH (git@host:path or host:path)
-	if strings.HasPrefix(repoURL, "git@") {
-		return repoURL, nil
-	}
-	if idx := strings.Index(repoURL, ":"); idx > 0 && !strings.Contains(repoURL[:idx], "/") && !strings.HasPrefix(repoURL, "http") {
-		return repoURL, nil
-	}
-	// GitHub HTTPS -> SSH
-	if strings.HasPrefix(repoURL, "https://github.com/") || strings.HasPrefix(repoURL, "http://github.com/") {
-		u, err := url.Parse(repoURL)
-		if err != nil {
-			return "", fmt.Errorf("invalid GitHub URL: %w", err)
-		}
-		path := strings.Trim(u.Path, "/")
-		path = strings.TrimSuffix(path, ".git")
-		if path == "" || !strings.Contains(path, "/") {
-			return "", fmt.Errorf("GitHub URL has no owner/repo path: %s", repoURL)
-		}
-		return "git@github.com:" + path + ".git", nil
-	}
-	// Generic HTTPS -> SSH (https://host/owner/repo -> git@host:owner/repo.git)
-	if strings.HasPrefix(repoURL, "https://") || strings.HasPrefix(repoURL, "http://") {
-		u, err := url.Parse(repoURL)
-		if err != nil {
-			return "", fmt.Errorf("invalid URL: %w", err)
-		}
-		path := strings.Trim(u.Path, "/")
-		path = strings.TrimSuffix(path, ".git")
-		if path == "" || !strings.Contains(path, "/") {
-			return "", fmt.Errorf("URL has no owner/repo path: %s", repoURL)
-		}
-		return "git@" + u.Host + ":" + path + ".git", nil
-	}
-	return "", fmt.Errorf("cannot convert to SSH: %s", repoURL)
-}
-
-func repoURLToHTTPS(repoURL string) (string, error) {
-	repoURL = strings.TrimSpace(repoURL)
-	// Already HTTPS
-	if strings.HasPrefix(repoURL, "https://") || strings.HasPrefix(repoURL, "http://") {
-		return repoURL, nil
-	}
-	// GitHub SSH -> HTTPS (git@github.com:owner/repo[.git] -> https://github.com/owner/repo)
-	if strings.HasPrefix(repoURL, "git@github.com:") {
-		path := strings.TrimPrefix(repoURL, "git@github.com:")
-		path = strings.TrimSuffix(path, ".git")
-		if path == "" || !strings.Contains(path, "/") {
-			return "", fmt.Errorf("GitHub SSH URL has no owner/repo path: %s", repoURL)
-		}
-		return "https://github.com/" + path, nil
-	}
-	// Generic SSH (host:owner/repo or git@host:owner/repo) -> HTTPS
-	if strings.HasPrefix(repoURL, "git@") {
-		rest := repoURL[len("git@"):]
-		idx := strings.Index(rest, ":")
-		if idx <= 0 {
-			return "", fmt.Errorf("SSH URL has no host:path: %s", repoURL)
-		}
-		host, path := rest[:idx], rest[idx+1:]
-		path = strings.TrimSuffix(path, ".git")
-		return "https://" + host + "/" + path, nil
+	ref, p, err := vcs.Parse(repoURL)
+	if err != nil {
+		return "", err
 	}
-	if idx := strings.Index(repoURL, ":"); idx > 0 && !strings.Contains(repoURL[:idx], "/") {
-		host, path := repoURL[:idx], repoURL[idx+1:]
-		path = strings.TrimSuffix(path, ".git")
-		return "https://" + host + "/" + path, nil
+	if useSSH {
+		return p.SSH(ref), nil
 	}
-	return "", fmt.Errorf("cannot convert to HTTPS: %s", repoURL)
+	return p.HTTPS(ref), nil
 }
 
-// forkURLFromUpstream returns the fork URL for the given username.
-// Handles GitHub HTTPS, GitHub SSH, and generic host URLs (replaces first path segment with username).
-func forkURLFromUpstream(repoURL, username string) (string, error) {
-	repoURL = strings.TrimSpace(repoURL)
+// forkURLFromUpstream returns the fork URL for the given username, in the
+// same scheme as useSSH, by substituting the owner on the parsed ref.
+func forkURLFromUpstream(repoURL, username string, useSSH bool) (string, error) {
 	username = strings.TrimSpace(username)
 	if username == "" {
 		return "", fmt.Errorf("username is empty")
 	}
-	// GitHub HTTPS: https://github.com/owner/repo[.git]
-	if strings.HasPrefix(repoURL, "https://github.com/") || strings.HasPrefix(repoURL, "http://github.com/") {
-		u, err := url.Parse(repoURL)
-		if err != nil {
-			return "", fmt.Errorf("invalid GitHub URL: %w", err)
-		}
-		path := strings.TrimPrefix(u.Path, "/")
-		path = strings.TrimSuffix(path, ".git")
-		parts := strings.SplitN(path, "/", 2)
-		if len(parts) < 2 {
-			return "", fmt.Errorf("GitHub URL has no owner/repo path: %s", repoURL)
-		}
-		u.Path = "/" + username + "/" + parts[1]
-		if strings.HasSuffix(repoURL, ".git") {
-			u.Path += ".git"
-		}
-		return u.String(), nil
-	}
-	// GitHub SSH: git@github.com:owner/repo[.git]
-	if strings.HasPrefix(repoURL, "git@github.com:") {
-		rest := strings.TrimPrefix(repoURL, "git@github.com:")
-		parts := strings.SplitN(rest, "/", 2)
-		if len(parts) < 2 {
-			return "", fmt.Errorf("GitHub SSH URL has no owner/repo path: %s", repoURL)
-		}
-		repo := strings.TrimSuffix(parts[1], ".git")
-		return "git@github.com:" + username + "/" + repo + ".git", nil
-	}
-	// Generic HTTPS: replace first path segment with username
-	if strings.HasPrefix(repoURL, "https://") || strings.HasPrefix(repoURL, "http://") {
-		u, err := url.Parse(repoURL)
-		if err != nil {
-			return "", fmt.Errorf("invalid URL: %w", err)
-		}
-		path := strings.Trim(u.Path, "/")
-		segments := strings.SplitN(path, "/", 2)
-		if len(segments) < 2 {
-			return "", fmt.Errorf("URL has no owner/repo path: %s", repoURL)
-		}
-		u.Path = "/" + username + "/" + segments[1]
-		return u.String(), nil
-	}
-	// Generic SSH: host:owner/repo -> host:username/repo
-	if idx := strings.Index(repoURL, ":"); idx > 0 && !strings.Contains(repoURL[:idx], "/") {
-		host := repoURL[:idx]
-		rest := repoURL[idx+1:]
-		parts := strings.SplitN(rest, "/", 2)
-		if len(parts) < 2 {
-			return "", fmt.Errorf("SSH URL has no owner/repo path: %s", repoURL)
-		}
-		return host + ":" + username + "/" + parts[1], nil
-	}
-	return "", fmt.Errorf("cannot derive fork URL from: %s", repoURL)
-}
-
-func cloneOrPull(targetPath, repoURL, username string) error {
-	gitDir := filepath.Join(targetPath, ".git")
-	exists := false
-	if fi, err := os.Stat(gitDir); err == nil && fi.IsDir() {
-		exists = true
-	}
-
-	if exists {
-		fmt.Fprintf(os.Stderr, "Pulling %s\n", targetPath)
-		if username != "" {
-			if err := ensureUpstreamOriginRemotes(targetPath, repoURL, username); err != nil {
-				return err
-			}
-			// Pull from upstream (branch tracks upstream when we cloned with -o upstream, or we renamed origin->upstream)
-			return runGit(exec.Command("git", "pull", "upstream"), targetPath)
-		}
-		return runGit(exec.Command("git", "pull"), targetPath)
-	}
-
-	if username != "" {
-		fmt.Fprintf(os.Stderr, "Cloning %s -> %s (upstream)\n", repoURL, targetPath)
-		if err := runGit(exec.Command("git", "clone", "-o", "upstream", repoURL, targetPath), "."); err != nil {
-			return err
-		}
-		forkURL, err := forkURLFromUpstream(repoURL, username)
-		if err != nil {
-			return err
-		}
-		return addOriginRemote(targetPath, forkURL)
-	}
-
-	fmt.Fprintf(os.Stderr, "Cloning %s -> %s\n", repoURL, targetPath)
-	return runGit(exec.Command("git", "clone", repoURL, targetPath), ".")
-}
-
-// ensureUpstreamOriginRemotes ensures upstream (project) and origin (fork) exist; normalizes repos cloned without --username.
-func ensureUpstreamOriginRemotes(targetPath, repoURL, username string) error {
-	hasUpstream := remoteExists(targetPath, "upstream")
-	hasOrigin := remoteExists(targetPath, "origin")
-
-	if hasUpstream && !hasOrigin {
-		forkURL, err := forkURLFromUpstream(repoURL, username)
-		if err != nil {
-			return err
-		}
-		return addOriginRemote(targetPath, forkURL)
-	}
-	if !hasUpstream && hasOrigin {
-		// Repo was cloned without --username; origin is the project. Rename to upstream and add origin as fork.
-		if err := runGit(exec.Command("git", "remote", "rename", "origin", "upstream"), targetPath); err != nil {
-			return err
-		}
-		forkURL, err := forkURLFromUpstream(repoURL, username)
-		if err != nil {
-			return err
-		}
-		return addOriginRemote(targetPath, forkURL)
+	ref, p, err := vcs.Parse(repoURL)
+	if err != nil {
+		return "", err
 	}
-	// Both exist or neither; if both exist we do nothing. If neither exists something is wrong; pull will fail.
-	return nil
-}
-
-func remoteExists(dir, name string) bool {
-	c := exec.Command("git", "remote", "get-url", name)
-	c.Dir = dir
-	c.Stdout = nil
-	c.Stderr = nil
-	return c.Run() == nil
-}
-
-// runGit runs cmd in dir, wiring stdout/stderr when !quiet.
-func runGit(cmd *exec.Cmd, dir string) error {
-	cmd.Dir = dir
-	if !quiet {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+	forkRef := p.WithOwner(ref, username)
+	if useSSH {
+		return p.SSH(forkRef), nil
 	}
-	return cmd.Run()
-}
-
-// addOriginRemote adds remote "origin" with url in the repo at targetPath.
-func addOriginRemote(targetPath, url string) error {
-	return runGit(exec.Command("git", "remote", "add", "origin", url), targetPath)
+	return p.HTTPS(forkRef), nil
 }